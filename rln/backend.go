@@ -0,0 +1,43 @@
+package rln
+
+import "unsafe"
+
+// Backend is the FFI surface an RLN instance needs from its native proving
+// library. It exists so the library backing a given build can be swapped
+// via build tag: the default build links kilic/rln (BN254, fixed
+// tree-depth-32 circuit), while the `zerokit` build tag swaps in the
+// arkworks-based zerokit library, which additionally supports configurable
+// tree depth and RLN v2 user-message-limit shares.
+//
+// Exactly one implementation is compiled into any given build: see
+// backend_kilic.go (default, build tag !zerokit) and backend_zerokit.go
+// (build tag zerokit).
+type Backend interface {
+	// NewRLN initializes a new native RLN instance of the given tree depth
+	// using the serialized proving/verification parameters, returning an
+	// opaque handle to it.
+	NewRLN(depth uint, params []byte) (unsafe.Pointer, error)
+
+	// NewRLNWithTreePath is like NewRLN, but backs the Merkle tree with
+	// persistent on-disk storage rooted at treePath instead of keeping it
+	// in memory, reopening any tree already present there.
+	NewRLNWithTreePath(depth uint, params []byte, treePath string) (unsafe.Pointer, error)
+
+	MembershipKeyGen(instance unsafe.Pointer) (MembershipKeyPair, error)
+	Hash(instance unsafe.Pointer, input []byte) (MerkleNode, error)
+	GenerateProof(instance unsafe.Pointer, msg []byte, key MembershipKeyPair, index MembershipIndex, epoch Epoch) (*RateLimitProof, error)
+	Verify(instance unsafe.Pointer, msg []byte, proof RateLimitProof) (bool, error)
+	InsertMember(instance unsafe.Pointer, commitment IDCommitment) (bool, error)
+	DeleteMember(instance unsafe.Pointer, index MembershipIndex) (bool, error)
+	GetMerkleRoot(instance unsafe.Pointer) (MerkleNode, error)
+
+	// InsertMembers pushes a contiguous batch of serialized commitments
+	// (concatenated IDCommitment bytes) starting at startIndex through the
+	// native library in one call, recomputing the Merkle root once for the
+	// whole batch.
+	InsertMembers(instance unsafe.Pointer, startIndex MembershipIndex, commitments []byte) (bool, error)
+
+	// DeleteMembers clears a batch of leaves in one call, recomputing the
+	// Merkle root once for the whole batch.
+	DeleteMembers(instance unsafe.Pointer, indices []MembershipIndex) (bool, error)
+}
@@ -0,0 +1,78 @@
+//go:build !zerokit
+
+package rln
+
+import "unsafe"
+
+// MembershipKeyPair holds the identity secret (IDKey) and its corresponding
+// Poseidon-hash commitment (IDCommitment) used by the kilic/rln backend.
+type MembershipKeyPair struct {
+	IDKey        IDKey
+	IDCommitment IDCommitment
+}
+
+// IdentitySecret returns the identity secret a keypair's IDCommitment was
+// derived from, under a name that is stable across backends whose
+// credential shapes otherwise differ (see backend_zerokit.go).
+func (kp MembershipKeyPair) IdentitySecret() IDKey {
+	return kp.IDKey
+}
+
+// newMembershipKeyPairFromSecret rebuilds a MembershipKeyPair from a
+// previously-derived (secret, commitment) pair, e.g. when loading a static
+// test fixture rather than generating a fresh key via MembershipKeyGen.
+func newMembershipKeyPairFromSecret(secret IDKey, commitment IDCommitment) MembershipKeyPair {
+	return MembershipKeyPair{IDKey: secret, IDCommitment: commitment}
+}
+
+// kilicBackend implements Backend against the kilic/rln native library,
+// the sole backend this repo supported before zerokit support was added.
+type kilicBackend struct{}
+
+func newBackend() Backend {
+	return kilicBackend{}
+}
+
+func (kilicBackend) NewRLN(depth uint, params []byte) (unsafe.Pointer, error) {
+	return kilicNewInstance(depth, params)
+}
+
+func (kilicBackend) NewRLNWithTreePath(depth uint, params []byte, treePath string) (unsafe.Pointer, error) {
+	return kilicNewInstanceWithTreePath(depth, params, treePath)
+}
+
+func (kilicBackend) MembershipKeyGen(instance unsafe.Pointer) (MembershipKeyPair, error) {
+	return kilicMembershipKeyGen(instance)
+}
+
+func (kilicBackend) Hash(instance unsafe.Pointer, input []byte) (MerkleNode, error) {
+	return kilicHash(instance, input)
+}
+
+func (kilicBackend) GenerateProof(instance unsafe.Pointer, msg []byte, key MembershipKeyPair, index MembershipIndex, epoch Epoch) (*RateLimitProof, error) {
+	return kilicGenerateProof(instance, msg, key, index, epoch)
+}
+
+func (kilicBackend) Verify(instance unsafe.Pointer, msg []byte, proof RateLimitProof) (bool, error) {
+	return kilicVerify(instance, msg, proof)
+}
+
+func (kilicBackend) InsertMember(instance unsafe.Pointer, commitment IDCommitment) (bool, error) {
+	return kilicInsertMember(instance, commitment)
+}
+
+func (kilicBackend) DeleteMember(instance unsafe.Pointer, index MembershipIndex) (bool, error) {
+	return kilicDeleteMember(instance, index)
+}
+
+func (kilicBackend) GetMerkleRoot(instance unsafe.Pointer) (MerkleNode, error) {
+	return kilicGetMerkleRoot(instance)
+}
+
+func (kilicBackend) InsertMembers(instance unsafe.Pointer, startIndex MembershipIndex, commitments []byte) (bool, error) {
+	return kilicInsertMembersBatch(instance, startIndex, commitments)
+}
+
+func (kilicBackend) DeleteMembers(instance unsafe.Pointer, indices []MembershipIndex) (bool, error) {
+	return kilicDeleteMembersBatch(instance, indices)
+}
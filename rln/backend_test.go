@@ -0,0 +1,23 @@
+package rln
+
+// TestBackendRoundTrip exercises the build-tag-selected Backend wired into
+// RLN end to end (key generation, insertion, and the resulting root
+// change), guarding against newBackend() being constructed but never
+// actually invoked by RLN's methods.
+func (s *RLNSuite) TestBackendRoundTrip() {
+	rln, err := NewRLNWithDepth(32, s.parameters)
+	s.NoError(err)
+
+	before, err := rln.GetMerkleRoot()
+	s.NoError(err)
+
+	keyPair, err := rln.MembershipKeyGen()
+	s.NoError(err)
+
+	s.True(rln.InsertMember(keyPair.IDCommitment))
+
+	after, err := rln.GetMerkleRoot()
+	s.NoError(err)
+
+	s.NotEqual(before, after)
+}
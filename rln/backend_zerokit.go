@@ -0,0 +1,94 @@
+//go:build zerokit
+
+package rln
+
+import "unsafe"
+
+// IDTrapdoor, IDNullifier and IDSecretHash are the additional secret
+// material zerokit's RLN v2 circuit derives the identity from (on top of
+// the IDCommitment shared with the kilic backend), enabling
+// user-message-limit shares instead of a single nullifier per epoch.
+type (
+	IDTrapdoor   [32]byte
+	IDNullifier  [32]byte
+	IDSecretHash [32]byte
+)
+
+// MembershipKeyPair holds the full zerokit identity credential: the
+// trapdoor and nullifier used to derive IDSecretHash, and the Poseidon-hash
+// IDCommitment derived from it in turn.
+type MembershipKeyPair struct {
+	IDTrapdoor   IDTrapdoor
+	IDNullifier  IDNullifier
+	IDSecretHash IDSecretHash
+	IDCommitment IDCommitment
+}
+
+// IdentitySecret returns the identity secret a keypair's IDCommitment was
+// derived from, under the same name backend_kilic.go exposes for the
+// kilic form, so shared tests don't need to know which backend is active.
+func (kp MembershipKeyPair) IdentitySecret() IDKey {
+	return IDKey(kp.IDSecretHash)
+}
+
+// newMembershipKeyPairFromSecret rebuilds a MembershipKeyPair from a
+// previously-derived (secret, commitment) pair, e.g. when loading a static
+// test fixture rather than generating a fresh key via MembershipKeyGen. The
+// zerokit credential shape has no room for a trapdoor/nullifier recovered
+// this way, so only the fields IdentitySecret/commitment verification
+// depend on are populated.
+func newMembershipKeyPairFromSecret(secret IDKey, commitment IDCommitment) MembershipKeyPair {
+	return MembershipKeyPair{IDSecretHash: IDSecretHash(secret), IDCommitment: commitment}
+}
+
+// zerokitBackend implements Backend against the arkworks-based zerokit
+// native library, which supports configurable tree depth and RLN v2.
+type zerokitBackend struct{}
+
+func newBackend() Backend {
+	return zerokitBackend{}
+}
+
+func (zerokitBackend) NewRLN(depth uint, params []byte) (unsafe.Pointer, error) {
+	return zerokitNewInstance(depth, params)
+}
+
+func (zerokitBackend) NewRLNWithTreePath(depth uint, params []byte, treePath string) (unsafe.Pointer, error) {
+	return zerokitNewInstanceWithTreePath(depth, params, treePath)
+}
+
+func (zerokitBackend) MembershipKeyGen(instance unsafe.Pointer) (MembershipKeyPair, error) {
+	return zerokitMembershipKeyGen(instance)
+}
+
+func (zerokitBackend) Hash(instance unsafe.Pointer, input []byte) (MerkleNode, error) {
+	return zerokitHash(instance, input)
+}
+
+func (zerokitBackend) GenerateProof(instance unsafe.Pointer, msg []byte, key MembershipKeyPair, index MembershipIndex, epoch Epoch) (*RateLimitProof, error) {
+	return zerokitGenerateProof(instance, msg, key, index, epoch)
+}
+
+func (zerokitBackend) Verify(instance unsafe.Pointer, msg []byte, proof RateLimitProof) (bool, error) {
+	return zerokitVerify(instance, msg, proof)
+}
+
+func (zerokitBackend) InsertMember(instance unsafe.Pointer, commitment IDCommitment) (bool, error) {
+	return zerokitInsertMember(instance, commitment)
+}
+
+func (zerokitBackend) DeleteMember(instance unsafe.Pointer, index MembershipIndex) (bool, error) {
+	return zerokitDeleteMember(instance, index)
+}
+
+func (zerokitBackend) GetMerkleRoot(instance unsafe.Pointer) (MerkleNode, error) {
+	return zerokitGetMerkleRoot(instance)
+}
+
+func (zerokitBackend) InsertMembers(instance unsafe.Pointer, startIndex MembershipIndex, commitments []byte) (bool, error) {
+	return zerokitInsertMembersBatch(instance, startIndex, commitments)
+}
+
+func (zerokitBackend) DeleteMembers(instance unsafe.Pointer, indices []MembershipIndex) (bool, error) {
+	return zerokitDeleteMembersBatch(instance, indices)
+}
@@ -0,0 +1,47 @@
+package rln
+
+import "fmt"
+
+// InsertMembers pushes a contiguous batch of commitments through the FFI in
+// a single call, starting at startIndex, recomputing the Merkle root once
+// for the whole batch instead of once per element. This is what makes
+// replaying the on-chain membership history on a cold start cheap: the
+// naive loop of InsertMember calls recomputes O(depth) hashes per element,
+// while a batch update only touches the subtrees affected by the whole
+// range.
+func (r *RLN) InsertMembers(startIndex MembershipIndex, commitments []IDCommitment) (bool, error) {
+	if len(commitments) == 0 {
+		return false, fmt.Errorf("no commitments to insert")
+	}
+
+	ok, err := r.backend.InsertMembers(r.instance, startIndex, serializeCommitments(commitments))
+	if err == nil {
+		r.syncRootMarker()
+	}
+	return ok, err
+}
+
+// DeleteMembers removes a batch of members identified by their tree indices
+// through the FFI in a single call, recomputing the Merkle root once rather
+// than once per deletion.
+func (r *RLN) DeleteMembers(indices []MembershipIndex) (bool, error) {
+	if len(indices) == 0 {
+		return false, fmt.Errorf("no indices to delete")
+	}
+
+	ok, err := r.backend.DeleteMembers(r.instance, indices)
+	if err == nil {
+		r.syncRootMarker()
+	}
+	return ok, err
+}
+
+// serializeCommitments concatenates a slice of IDCommitment into the flat
+// byte buffer the FFI batch-insert call expects.
+func serializeCommitments(commitments []IDCommitment) []byte {
+	var out []byte
+	for _, c := range commitments {
+		out = append(out, c[:]...)
+	}
+	return out
+}
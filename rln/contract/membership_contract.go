@@ -0,0 +1,222 @@
+// Package contract contains the generated bindings for the on-chain RLN
+// membership contract used by the on-chain group manager. It mirrors the
+// contract nwaku's rln-relay registers against: a MEMBERSHIP_DEPOSIT-based
+// registry that emits MemberRegistered/MemberWithdrawn events and exposes a
+// register() payable method.
+//
+// The bindings below are intentionally hand-trimmed to the subset of the ABI
+// the group manager actually needs, rather than a full abigen dump of the
+// contract.
+package contract
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// membershipContractABI is the minimal ABI surface used by the RLN
+// membership registry: registering a new member and the two events that
+// drive local Merkle tree synchronization.
+const membershipContractABI = `[
+	{"anonymous":false,"inputs":[{"indexed":false,"internalType":"uint256","name":"pubkey","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"index","type":"uint256"}],"name":"MemberRegistered","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":false,"internalType":"uint256","name":"pubkey","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"index","type":"uint256"}],"name":"MemberWithdrawn","type":"event"},
+	{"inputs":[{"internalType":"uint256","name":"pubkey","type":"uint256"}],"name":"register","outputs":[],"stateMutability":"payable","type":"function"},
+	{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"members","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"MEMBERSHIP_DEPOSIT","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}
+]`
+
+// MembershipContract is a thin wrapper around bind.BoundContract for the RLN
+// membership registry.
+type MembershipContract struct {
+	address common.Address
+	abi     abi.ABI
+	*bind.BoundContract
+}
+
+// NewMembershipContract binds a MembershipContract to a concrete deployed
+// contract on the given chain backend.
+func NewMembershipContract(address common.Address, backend bind.ContractBackend) (*MembershipContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(membershipContractABI))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MembershipContract{
+		address:       address,
+		abi:           parsed,
+		BoundContract: bind.NewBoundContract(address, parsed, backend, backend, backend),
+	}, nil
+}
+
+// MembershipDeposit returns the MEMBERSHIP_DEPOSIT amount (in wei) a caller
+// must send along with Register.
+func (c *MembershipContract) MembershipDeposit(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := c.Call(opts, &out, "MEMBERSHIP_DEPOSIT")
+	if err != nil {
+		return nil, err
+	}
+	return out[0].(*big.Int), nil
+}
+
+// Register submits the identity commitment (pubkey) to the contract,
+// attaching MembershipDeposit wei via opts.Value.
+func (c *MembershipContract) Register(opts *bind.TransactOpts, pubkey *big.Int) (*types.Transaction, error) {
+	return c.Transact(opts, "register", pubkey)
+}
+
+// MemberRegistered is the Go representation of the contract's
+// MemberRegistered(uint256 pubkey, uint256 index) event.
+type MemberRegistered struct {
+	Pubkey *big.Int
+	Index  *big.Int
+	Raw    types.Log
+}
+
+// MemberWithdrawn is the Go representation of the contract's
+// MemberWithdrawn(uint256 pubkey, uint256 index) event.
+type MemberWithdrawn struct {
+	Pubkey *big.Int
+	Index  *big.Int
+	Raw    types.Log
+}
+
+// FilterMemberRegistered replays historical MemberRegistered events in
+// [opts.Start, opts.End], used on startup to resync the local Merkle tree.
+func (c *MembershipContract) FilterMemberRegistered(opts *bind.FilterOpts) ([]*MemberRegistered, error) {
+	logs, sub, err := c.FilterLogs(opts, "MemberRegistered")
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+
+	var events []*MemberRegistered
+	for {
+		select {
+		case log, ok := <-logs:
+			if !ok {
+				return events, nil
+			}
+			var event MemberRegistered
+			if err := c.UnpackLog(&event, "MemberRegistered", log); err != nil {
+				return nil, err
+			}
+			event.Raw = log
+			events = append(events, &event)
+		case err := <-sub.Err():
+			// The subscription ends with a nil error once every filtered
+			// log has been queued on logs; keep reading until none are left.
+			if err != nil {
+				return nil, err
+			}
+			if len(logs) == 0 {
+				return events, nil
+			}
+		}
+	}
+}
+
+// FilterMemberWithdrawn replays historical MemberWithdrawn events in
+// [opts.Start, opts.End].
+func (c *MembershipContract) FilterMemberWithdrawn(opts *bind.FilterOpts) ([]*MemberWithdrawn, error) {
+	logs, sub, err := c.FilterLogs(opts, "MemberWithdrawn")
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+
+	var events []*MemberWithdrawn
+	for {
+		select {
+		case log, ok := <-logs:
+			if !ok {
+				return events, nil
+			}
+			var event MemberWithdrawn
+			if err := c.UnpackLog(&event, "MemberWithdrawn", log); err != nil {
+				return nil, err
+			}
+			event.Raw = log
+			events = append(events, &event)
+		case err := <-sub.Err():
+			// The subscription ends with a nil error once every filtered
+			// log has been queued on logs; keep reading until none are left.
+			if err != nil {
+				return nil, err
+			}
+			if len(logs) == 0 {
+				return events, nil
+			}
+		}
+	}
+}
+
+// WatchMemberRegistered subscribes to new MemberRegistered events, pushing
+// them onto sink until the subscription is cancelled.
+func (c *MembershipContract) WatchMemberRegistered(opts *bind.WatchOpts, sink chan<- *MemberRegistered) (event.Subscription, error) {
+	logs, sub, err := c.WatchLogs(opts, "MemberRegistered")
+	if err != nil {
+		return nil, err
+	}
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				var e MemberRegistered
+				if err := c.UnpackLog(&e, "MemberRegistered", log); err != nil {
+					return err
+				}
+				e.Raw = log
+				select {
+				case sink <- &e:
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// WatchMemberWithdrawn subscribes to new MemberWithdrawn events, pushing
+// them onto sink until the subscription is cancelled.
+func (c *MembershipContract) WatchMemberWithdrawn(opts *bind.WatchOpts, sink chan<- *MemberWithdrawn) (event.Subscription, error) {
+	logs, sub, err := c.WatchLogs(opts, "MemberWithdrawn")
+	if err != nil {
+		return nil, err
+	}
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				var e MemberWithdrawn
+				if err := c.UnpackLog(&e, "MemberWithdrawn", log); err != nil {
+					return err
+				}
+				e.Raw = log
+				select {
+				case sink <- &e:
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
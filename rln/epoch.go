@@ -0,0 +1,23 @@
+package rln
+
+import "encoding/binary"
+
+// ToEpoch encodes a unix-like timestamp as an Epoch: the big-endian uint64
+// in the first 8 bytes, the remaining 24 bytes zeroed. Proofs generated
+// within the same ToEpoch(t) share a nullifier space, matching nwaku's
+// epoch-bucketing of the RLN rate limit.
+func ToEpoch(t uint64) Epoch {
+	var e Epoch
+	binary.BigEndian.PutUint64(e[:8], t)
+	return e
+}
+
+// Uint64 decodes the timestamp ToEpoch encoded into e.
+func (e Epoch) Uint64() uint64 {
+	return binary.BigEndian.Uint64(e[:8])
+}
+
+// Diff returns e1's distance from e2 in epochs, positive if e1 is later.
+func Diff(e1, e2 Epoch) int64 {
+	return int64(e1.Uint64()) - int64(e2.Uint64())
+}
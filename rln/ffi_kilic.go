@@ -0,0 +1,373 @@
+//go:build !zerokit
+
+package rln
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+)
+
+// This file is the kilic/rln FFI shim: the set of package-level functions
+// backend_kilic.go's kilicBackend calls through unsafe.Pointer handles, in
+// place of the real cgo bindings into the kilic/rln Rust crate (BN254,
+// fixed tree-depth-32 circuit). Until those cgo bindings are wired in, it
+// is a deterministic, self-contained pure-Go stand-in: a real sparse
+// Merkle tree and a real (non-Poseidon) hash function, so every exported
+// RLN method has genuine, backend-specific behaviour to call into rather
+// than an undefined symbol. It deliberately does not share code with
+// ffi_zerokit.go - the two are meant to model two distinct native
+// libraries, not two call sites into the same logic.
+
+// kilicDomain separates kilic's hash outputs from zerokit's, so the two
+// backends never produce the same root/commitment for the same input even
+// though they both fall back to SHA-256 under the hood.
+const kilicDomain = "kilic-rln-v1"
+
+// kilicDigest is the placeholder for the real kilic/rln Poseidon hash.
+func kilicDigest(parts ...[]byte) MerkleNode {
+	h := sha256.New()
+	h.Write([]byte(kilicDomain))
+	for _, p := range parts {
+		h.Write(p)
+	}
+	var out MerkleNode
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// kilicTree is a sparse Merkle tree over kilicHash. Only non-zero leaves
+// are kept in memory; zeroHashes[i] is the hash of an all-zero subtree of
+// height i, precomputed once so the root can be derived without
+// materializing every one of the 2^depth leaves.
+type kilicTree struct {
+	depth      uint
+	zeroHashes []MerkleNode
+	leaves     map[MembershipIndex]MerkleNode
+}
+
+func newKilicTree(depth uint) *kilicTree {
+	zeroHashes := make([]MerkleNode, depth+1)
+	for i := uint(1); i <= depth; i++ {
+		zeroHashes[i] = kilicDigest(zeroHashes[i-1][:], zeroHashes[i-1][:])
+	}
+	return &kilicTree{depth: depth, zeroHashes: zeroHashes, leaves: make(map[MembershipIndex]MerkleNode)}
+}
+
+// root computes the tree's root bottom-up, starting from only the non-zero
+// leaves and folding siblings level by level (defaulting the untouched side
+// of each pair to the precomputed zero hash for that level). This costs
+// O(len(leaves) * depth) hashes rather than the O(2^depth) a naive
+// top-down walk of a depth-32 tree would need.
+func (t *kilicTree) root() MerkleNode {
+	if len(t.leaves) == 0 {
+		return t.zeroHashes[t.depth]
+	}
+
+	level := make(map[uint64]MerkleNode, len(t.leaves))
+	for index, leaf := range t.leaves {
+		level[uint64(index)] = leaf
+	}
+
+	for depth := uint(0); depth < t.depth; depth++ {
+		next := make(map[uint64]MerkleNode, len(level))
+		for index := range level {
+			parent := index / 2
+			if _, done := next[parent]; done {
+				continue
+			}
+
+			left, ok := level[parent*2]
+			if !ok {
+				left = t.zeroHashes[depth]
+			}
+			right, ok := level[parent*2+1]
+			if !ok {
+				right = t.zeroHashes[depth]
+			}
+
+			next[parent] = kilicDigest(left[:], right[:])
+		}
+		level = next
+	}
+
+	return level[0]
+}
+
+// kilicRLN is the state a single kilicNewInstance handle refers to.
+type kilicRLN struct {
+	mu            sync.Mutex
+	tree          *kilicTree
+	nextIndex     MembershipIndex
+	treePath      string
+	proofValidity map[string]bool
+}
+
+func kilicInstanceFromPointer(instance unsafe.Pointer) *kilicRLN {
+	return (*kilicRLN)(instance)
+}
+
+func kilicNewInstance(depth uint, params []byte) (unsafe.Pointer, error) {
+	if depth == 0 {
+		return nil, fmt.Errorf("tree depth must be greater than zero")
+	}
+	if len(params) == 0 {
+		return nil, fmt.Errorf("missing proving/verification parameters")
+	}
+
+	inst := &kilicRLN{
+		tree:          newKilicTree(depth),
+		proofValidity: make(map[string]bool),
+	}
+	return unsafe.Pointer(inst), nil
+}
+
+func kilicNewInstanceWithTreePath(depth uint, params []byte, treePath string) (unsafe.Pointer, error) {
+	raw, err := kilicNewInstance(depth, params)
+	if err != nil {
+		return nil, err
+	}
+	inst := kilicInstanceFromPointer(raw)
+	inst.treePath = treePath
+
+	if err := kilicLoadTree(inst); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// kilicPersistedTree is the on-disk representation of a kilicRLN's tree
+// state, written to <treePath>/kilic_tree.json after every mutation so a
+// later kilicNewInstanceWithTreePath against the same path reopens it
+// instead of starting empty.
+type kilicPersistedTree struct {
+	Depth     uint                       `json:"depth"`
+	NextIndex MembershipIndex            `json:"nextIndex"`
+	Leaves    map[MembershipIndex]string `json:"leaves"`
+}
+
+func kilicTreeFile(treePath string) string {
+	return filepath.Join(treePath, "kilic_tree.json")
+}
+
+func kilicLoadTree(inst *kilicRLN) error {
+	if inst.treePath == "" {
+		return nil
+	}
+
+	raw, err := os.ReadFile(kilicTreeFile(inst.treePath))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading persisted tree: %w", err)
+	}
+
+	var persisted kilicPersistedTree
+	if err := json.Unmarshal(raw, &persisted); err != nil {
+		return fmt.Errorf("parsing persisted tree: %w", err)
+	}
+
+	inst.tree = newKilicTree(persisted.Depth)
+	inst.nextIndex = persisted.NextIndex
+	for idx, hexLeaf := range persisted.Leaves {
+		decoded, err := hex.DecodeString(hexLeaf)
+		if err != nil || len(decoded) != len(MerkleNode{}) {
+			return fmt.Errorf("parsing persisted leaf %d: invalid hex", idx)
+		}
+		var leaf MerkleNode
+		copy(leaf[:], decoded)
+		inst.tree.leaves[idx] = leaf
+	}
+
+	return nil
+}
+
+func kilicPersistTree(inst *kilicRLN) error {
+	if inst.treePath == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(inst.treePath, 0o755); err != nil {
+		return fmt.Errorf("creating tree path: %w", err)
+	}
+
+	persisted := kilicPersistedTree{
+		Depth:     inst.tree.depth,
+		NextIndex: inst.nextIndex,
+		Leaves:    make(map[MembershipIndex]string, len(inst.tree.leaves)),
+	}
+	for idx, leaf := range inst.tree.leaves {
+		persisted.Leaves[idx] = hex.EncodeToString(leaf[:])
+	}
+
+	raw, err := json.Marshal(persisted)
+	if err != nil {
+		return fmt.Errorf("marshaling persisted tree: %w", err)
+	}
+
+	return os.WriteFile(kilicTreeFile(inst.treePath), raw, 0o644)
+}
+
+func kilicMembershipKeyGen(instance unsafe.Pointer) (MembershipKeyPair, error) {
+	var raw [32]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return MembershipKeyPair{}, fmt.Errorf("generating identity secret: %w", err)
+	}
+
+	// Reduce into the scalar field up front so the secret this keypair
+	// reports is exactly what recoverIDSecret's Lagrange interpolation (also
+	// done mod bn254FrModulus) will reconstruct from two of its proofs; a
+	// secret sampled from the full 32 bytes would only round-trip when it
+	// happened to already be smaller than the field modulus.
+	secretInt := new(big.Int).Mod(new(big.Int).SetBytes(raw[:]), bn254FrModulus)
+	var secret IDKey
+	secretInt.FillBytes(secret[:])
+
+	commitment := kilicDigest(secret[:])
+	return MembershipKeyPair{IDKey: secret, IDCommitment: IDCommitment(commitment)}, nil
+}
+
+func kilicHash(instance unsafe.Pointer, input []byte) (MerkleNode, error) {
+	return kilicDigest(input), nil
+}
+
+func kilicInsertMember(instance unsafe.Pointer, commitment IDCommitment) (bool, error) {
+	inst := kilicInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	inst.tree.leaves[inst.nextIndex] = MerkleNode(commitment)
+	inst.nextIndex++
+
+	if err := kilicPersistTree(inst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func kilicDeleteMember(instance unsafe.Pointer, index MembershipIndex) (bool, error) {
+	inst := kilicInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	delete(inst.tree.leaves, index)
+
+	if err := kilicPersistTree(inst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func kilicGetMerkleRoot(instance unsafe.Pointer) (MerkleNode, error) {
+	inst := kilicInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	return inst.tree.root(), nil
+}
+
+func kilicInsertMembersBatch(instance unsafe.Pointer, startIndex MembershipIndex, commitments []byte) (bool, error) {
+	if len(commitments)%32 != 0 {
+		return false, fmt.Errorf("commitments buffer is not a multiple of 32 bytes")
+	}
+
+	inst := kilicInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	count := MembershipIndex(len(commitments) / 32)
+	for i := MembershipIndex(0); i < count; i++ {
+		var leaf MerkleNode
+		copy(leaf[:], commitments[i*32:(i+1)*32])
+		inst.tree.leaves[startIndex+i] = leaf
+	}
+	if startIndex+count > inst.nextIndex {
+		inst.nextIndex = startIndex + count
+	}
+
+	if err := kilicPersistTree(inst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func kilicDeleteMembersBatch(instance unsafe.Pointer, indices []MembershipIndex) (bool, error) {
+	inst := kilicInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	for _, idx := range indices {
+		delete(inst.tree.leaves, idx)
+	}
+
+	if err := kilicPersistTree(inst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// kilicProofKey identifies a proof by its public fields, so kilicVerify can
+// look up whether kilicGenerateProof considered it valid without the
+// caller re-supplying the identity secret or claimed index.
+func kilicProofKey(proof RateLimitProof) string {
+	return string(proof.Nullifier[:]) + string(proof.ShareX[:]) + string(proof.ShareY[:]) + string(proof.Epoch[:])
+}
+
+func kilicGenerateProof(instance unsafe.Pointer, msg []byte, key MembershipKeyPair, index MembershipIndex, epoch Epoch) (*RateLimitProof, error) {
+	inst := kilicInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	secret := key.IdentitySecret()
+	expectedCommitment := kilicDigest(secret[:])
+	leaf, isMember := inst.tree.leaves[index]
+	valid := isMember && leaf == expectedCommitment
+
+	secretInt := new(big.Int).Mod(new(big.Int).SetBytes(secret[:]), bn254FrModulus)
+
+	slopeNode := kilicDigest([]byte("kilic-a1"), secret[:], epoch[:])
+	slope := new(big.Int).Mod(new(big.Int).SetBytes(slopeNode[:]), bn254FrModulus)
+
+	shareXNode := kilicDigest([]byte("kilic-shareX"), msg, epoch[:])
+	shareX := new(big.Int).Mod(new(big.Int).SetBytes(shareXNode[:]), bn254FrModulus)
+
+	shareY := new(big.Int).Mod(new(big.Int).Add(secretInt, new(big.Int).Mul(slope, shareX)), bn254FrModulus)
+
+	nullifierNode := kilicDigest([]byte("kilic-nullifier"), secret[:], epoch[:])
+
+	proof := &RateLimitProof{Epoch: epoch}
+	copy(proof.Nullifier[:], nullifierNode[:])
+	shareX.FillBytes(proof.ShareX[:])
+	shareY.FillBytes(proof.ShareY[:])
+
+	inst.proofValidity[kilicProofKey(*proof)] = valid
+	return proof, nil
+}
+
+func kilicVerify(instance unsafe.Pointer, msg []byte, proof RateLimitProof) (bool, error) {
+	inst := kilicInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	valid, seen := inst.proofValidity[kilicProofKey(proof)]
+	if !seen || !valid {
+		return false, nil
+	}
+
+	expectedShareXNode := kilicDigest([]byte("kilic-shareX"), msg, proof.Epoch[:])
+	expectedShareX := new(big.Int).Mod(new(big.Int).SetBytes(expectedShareXNode[:]), bn254FrModulus)
+
+	var expected MerkleNode
+	expectedShareX.FillBytes(expected[:])
+	return bytes.Equal(expected[:], proof.ShareX[:]), nil
+}
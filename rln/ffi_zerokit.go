@@ -0,0 +1,381 @@
+//go:build zerokit
+
+package rln
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+)
+
+// This file is the zerokit FFI shim: the set of package-level functions
+// backend_zerokit.go's zerokitBackend calls through unsafe.Pointer handles,
+// in place of the real cgo bindings into the zerokit Rust crate (arkworks,
+// configurable tree depth, RLN v2 credentials). Until those cgo bindings are
+// wired in, it is a deterministic, self-contained pure-Go stand-in, built
+// independently of ffi_kilic.go: its own domain-separated hash, its own
+// persistence format, and its own handling of the trapdoor/nullifier
+// credential shape zerokit's circuit uses instead of kilic's single IDKey.
+
+// zerokitDomain separates zerokit's hash outputs from kilic's, so the two
+// backends never produce the same root/commitment for the same input even
+// though they both fall back to SHA-256 under the hood.
+const zerokitDomain = "zerokit-rln-v2"
+
+// zerokitDigest is the placeholder for the real zerokit Poseidon hash.
+func zerokitDigest(parts ...[]byte) MerkleNode {
+	h := sha256.New()
+	h.Write([]byte(zerokitDomain))
+	for _, p := range parts {
+		h.Write(p)
+	}
+	var out MerkleNode
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// zerokitTree is a sparse Merkle tree over zerokitDigest. Only non-zero
+// leaves are kept in memory; zeroHashes[i] is the hash of an all-zero
+// subtree of height i, precomputed once so the root can be derived without
+// materializing every one of the 2^depth leaves.
+type zerokitTree struct {
+	depth      uint
+	zeroHashes []MerkleNode
+	leaves     map[MembershipIndex]MerkleNode
+}
+
+func newZerokitTree(depth uint) *zerokitTree {
+	zeroHashes := make([]MerkleNode, depth+1)
+	for i := uint(1); i <= depth; i++ {
+		zeroHashes[i] = zerokitDigest(zeroHashes[i-1][:], zeroHashes[i-1][:])
+	}
+	return &zerokitTree{depth: depth, zeroHashes: zeroHashes, leaves: make(map[MembershipIndex]MerkleNode)}
+}
+
+// root computes the tree's root bottom-up, starting from only the non-zero
+// leaves and folding siblings level by level (defaulting the untouched side
+// of each pair to the precomputed zero hash for that level). This costs
+// O(len(leaves) * depth) hashes rather than the O(2^depth) a naive top-down
+// walk of a deep tree would need.
+func (t *zerokitTree) root() MerkleNode {
+	if len(t.leaves) == 0 {
+		return t.zeroHashes[t.depth]
+	}
+
+	level := make(map[uint64]MerkleNode, len(t.leaves))
+	for index, leaf := range t.leaves {
+		level[uint64(index)] = leaf
+	}
+
+	for depth := uint(0); depth < t.depth; depth++ {
+		next := make(map[uint64]MerkleNode, len(level))
+		for index := range level {
+			parent := index / 2
+			if _, done := next[parent]; done {
+				continue
+			}
+
+			left, ok := level[parent*2]
+			if !ok {
+				left = t.zeroHashes[depth]
+			}
+			right, ok := level[parent*2+1]
+			if !ok {
+				right = t.zeroHashes[depth]
+			}
+
+			next[parent] = zerokitDigest(left[:], right[:])
+		}
+		level = next
+	}
+
+	return level[0]
+}
+
+// zerokitRLN is the state a single zerokitNewInstance handle refers to.
+type zerokitRLN struct {
+	mu            sync.Mutex
+	tree          *zerokitTree
+	nextIndex     MembershipIndex
+	treePath      string
+	proofValidity map[string]bool
+}
+
+func zerokitInstanceFromPointer(instance unsafe.Pointer) *zerokitRLN {
+	return (*zerokitRLN)(instance)
+}
+
+func zerokitNewInstance(depth uint, params []byte) (unsafe.Pointer, error) {
+	if depth == 0 {
+		return nil, fmt.Errorf("tree depth must be greater than zero")
+	}
+	if len(params) == 0 {
+		return nil, fmt.Errorf("missing proving/verification parameters")
+	}
+
+	inst := &zerokitRLN{
+		tree:          newZerokitTree(depth),
+		proofValidity: make(map[string]bool),
+	}
+	return unsafe.Pointer(inst), nil
+}
+
+func zerokitNewInstanceWithTreePath(depth uint, params []byte, treePath string) (unsafe.Pointer, error) {
+	raw, err := zerokitNewInstance(depth, params)
+	if err != nil {
+		return nil, err
+	}
+	inst := zerokitInstanceFromPointer(raw)
+	inst.treePath = treePath
+
+	if err := zerokitLoadTree(inst); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// zerokitPersistedTree is the on-disk representation of a zerokitRLN's tree
+// state, written to <treePath>/zerokit_tree.json after every mutation so a
+// later zerokitNewInstanceWithTreePath against the same path reopens it instead
+// of starting empty.
+type zerokitPersistedTree struct {
+	Depth     uint                       `json:"depth"`
+	NextIndex MembershipIndex            `json:"nextIndex"`
+	Leaves    map[MembershipIndex]string `json:"leaves"`
+}
+
+func zerokitTreeFile(treePath string) string {
+	return filepath.Join(treePath, "zerokit_tree.json")
+}
+
+func zerokitLoadTree(inst *zerokitRLN) error {
+	if inst.treePath == "" {
+		return nil
+	}
+
+	raw, err := os.ReadFile(zerokitTreeFile(inst.treePath))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading persisted tree: %w", err)
+	}
+
+	var persisted zerokitPersistedTree
+	if err := json.Unmarshal(raw, &persisted); err != nil {
+		return fmt.Errorf("parsing persisted tree: %w", err)
+	}
+
+	inst.tree = newZerokitTree(persisted.Depth)
+	inst.nextIndex = persisted.NextIndex
+	for idx, hexLeaf := range persisted.Leaves {
+		decoded, err := hex.DecodeString(hexLeaf)
+		if err != nil || len(decoded) != len(MerkleNode{}) {
+			return fmt.Errorf("parsing persisted leaf %d: invalid hex", idx)
+		}
+		var leaf MerkleNode
+		copy(leaf[:], decoded)
+		inst.tree.leaves[idx] = leaf
+	}
+
+	return nil
+}
+
+func zerokitPersistTree(inst *zerokitRLN) error {
+	if inst.treePath == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(inst.treePath, 0o755); err != nil {
+		return fmt.Errorf("creating tree path: %w", err)
+	}
+
+	persisted := zerokitPersistedTree{
+		Depth:     inst.tree.depth,
+		NextIndex: inst.nextIndex,
+		Leaves:    make(map[MembershipIndex]string, len(inst.tree.leaves)),
+	}
+	for idx, leaf := range inst.tree.leaves {
+		persisted.Leaves[idx] = hex.EncodeToString(leaf[:])
+	}
+
+	raw, err := json.Marshal(persisted)
+	if err != nil {
+		return fmt.Errorf("marshaling persisted tree: %w", err)
+	}
+
+	return os.WriteFile(zerokitTreeFile(inst.treePath), raw, 0o644)
+}
+
+func zerokitMembershipKeyGen(instance unsafe.Pointer) (MembershipKeyPair, error) {
+	var trapdoor IDTrapdoor
+	var nullifier IDNullifier
+	if _, err := rand.Read(trapdoor[:]); err != nil {
+		return MembershipKeyPair{}, fmt.Errorf("generating identity trapdoor: %w", err)
+	}
+	if _, err := rand.Read(nullifier[:]); err != nil {
+		return MembershipKeyPair{}, fmt.Errorf("generating identity nullifier: %w", err)
+	}
+
+	// Reduce into the scalar field up front so the secret hash this keypair
+	// reports is exactly what recoverIDSecret's Lagrange interpolation (also
+	// done mod bn254FrModulus) will reconstruct from two of its proofs; a
+	// secret hash sampled from the full digest would only round-trip when it
+	// happened to already be smaller than the field modulus.
+	secretHashNode := zerokitDigest(trapdoor[:], nullifier[:])
+	secretHashInt := new(big.Int).Mod(new(big.Int).SetBytes(secretHashNode[:]), bn254FrModulus)
+	var secretHash IDSecretHash
+	secretHashInt.FillBytes(secretHash[:])
+
+	commitment := zerokitDigest(secretHash[:])
+	return MembershipKeyPair{
+		IDTrapdoor:   trapdoor,
+		IDNullifier:  nullifier,
+		IDSecretHash: secretHash,
+		IDCommitment: IDCommitment(commitment),
+	}, nil
+}
+
+func zerokitHash(instance unsafe.Pointer, input []byte) (MerkleNode, error) {
+	return zerokitDigest(input), nil
+}
+
+func zerokitInsertMember(instance unsafe.Pointer, commitment IDCommitment) (bool, error) {
+	inst := zerokitInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	inst.tree.leaves[inst.nextIndex] = MerkleNode(commitment)
+	inst.nextIndex++
+
+	if err := zerokitPersistTree(inst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func zerokitDeleteMember(instance unsafe.Pointer, index MembershipIndex) (bool, error) {
+	inst := zerokitInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	delete(inst.tree.leaves, index)
+
+	if err := zerokitPersistTree(inst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func zerokitGetMerkleRoot(instance unsafe.Pointer) (MerkleNode, error) {
+	inst := zerokitInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	return inst.tree.root(), nil
+}
+
+func zerokitInsertMembersBatch(instance unsafe.Pointer, startIndex MembershipIndex, commitments []byte) (bool, error) {
+	if len(commitments)%32 != 0 {
+		return false, fmt.Errorf("commitments buffer is not a multiple of 32 bytes")
+	}
+
+	inst := zerokitInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	count := MembershipIndex(len(commitments) / 32)
+	for i := MembershipIndex(0); i < count; i++ {
+		var leaf MerkleNode
+		copy(leaf[:], commitments[i*32:(i+1)*32])
+		inst.tree.leaves[startIndex+i] = leaf
+	}
+	if startIndex+count > inst.nextIndex {
+		inst.nextIndex = startIndex + count
+	}
+
+	if err := zerokitPersistTree(inst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func zerokitDeleteMembersBatch(instance unsafe.Pointer, indices []MembershipIndex) (bool, error) {
+	inst := zerokitInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	for _, idx := range indices {
+		delete(inst.tree.leaves, idx)
+	}
+
+	if err := zerokitPersistTree(inst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// zerokitProofKey identifies a proof by its public fields, so zerokitVerify
+// can look up whether zerokitGenerateProof considered it valid without the caller
+// re-supplying the identity secret or claimed index.
+func zerokitProofKey(proof RateLimitProof) string {
+	return string(proof.Nullifier[:]) + string(proof.ShareX[:]) + string(proof.ShareY[:]) + string(proof.Epoch[:])
+}
+
+func zerokitGenerateProof(instance unsafe.Pointer, msg []byte, key MembershipKeyPair, index MembershipIndex, epoch Epoch) (*RateLimitProof, error) {
+	inst := zerokitInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	secret := key.IdentitySecret()
+	expectedCommitment := zerokitDigest(secret[:])
+	leaf, isMember := inst.tree.leaves[index]
+	valid := isMember && leaf == expectedCommitment
+
+	secretInt := new(big.Int).Mod(new(big.Int).SetBytes(secret[:]), bn254FrModulus)
+
+	slopeNode := zerokitDigest([]byte("zerokit-a1"), secret[:], epoch[:])
+	slope := new(big.Int).Mod(new(big.Int).SetBytes(slopeNode[:]), bn254FrModulus)
+
+	shareXNode := zerokitDigest([]byte("zerokit-shareX"), msg, epoch[:])
+	shareX := new(big.Int).Mod(new(big.Int).SetBytes(shareXNode[:]), bn254FrModulus)
+
+	shareY := new(big.Int).Mod(new(big.Int).Add(secretInt, new(big.Int).Mul(slope, shareX)), bn254FrModulus)
+
+	nullifierNode := zerokitDigest([]byte("zerokit-nullifier"), secret[:], epoch[:])
+
+	proof := &RateLimitProof{Epoch: epoch}
+	copy(proof.Nullifier[:], nullifierNode[:])
+	shareX.FillBytes(proof.ShareX[:])
+	shareY.FillBytes(proof.ShareY[:])
+
+	inst.proofValidity[zerokitProofKey(*proof)] = valid
+	return proof, nil
+}
+
+func zerokitVerify(instance unsafe.Pointer, msg []byte, proof RateLimitProof) (bool, error) {
+	inst := zerokitInstanceFromPointer(instance)
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	valid, seen := inst.proofValidity[zerokitProofKey(proof)]
+	if !seen || !valid {
+		return false, nil
+	}
+
+	expectedShareXNode := zerokitDigest([]byte("zerokit-shareX"), msg, proof.Epoch[:])
+	expectedShareX := new(big.Int).Mod(new(big.Int).SetBytes(expectedShareXNode[:]), bn254FrModulus)
+
+	var expected MerkleNode
+	expectedShareX.FillBytes(expected[:])
+	return bytes.Equal(expected[:], proof.ShareX[:]), nil
+}
@@ -0,0 +1,112 @@
+package rln
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// HASH_HEX_SIZE is the length of a MerkleNode/IDCommitment hex-encoded with
+// encoding/hex (32 bytes, two hex characters per byte).
+const HASH_HEX_SIZE = 2 * len(MerkleNode{})
+
+// CreateMembershipList generates groupSize fresh membership keypairs,
+// inserts them all into a new in-memory RLN instance in order, and returns
+// the resulting keypairs alongside the hex-encoded Merkle root, mirroring
+// nwaku's static_lists test helper used to bootstrap a group off-chain.
+func CreateMembershipList(groupSize int, params []byte) ([]MembershipKeyPair, string, error) {
+	r, err := NewRLN(params)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating RLN instance: %w", err)
+	}
+
+	list := make([]MembershipKeyPair, 0, groupSize)
+	for i := 0; i < groupSize; i++ {
+		keyPair, err := r.MembershipKeyGen()
+		if err != nil {
+			return nil, "", fmt.Errorf("generating membership key %d: %w", i, err)
+		}
+		if !r.InsertMember(keyPair.IDCommitment) {
+			return nil, "", fmt.Errorf("inserting membership key %d", i)
+		}
+		list = append(list, *keyPair)
+	}
+
+	root, err := r.GetMerkleRoot()
+	if err != nil {
+		return nil, "", fmt.Errorf("reading merkle root: %w", err)
+	}
+
+	return list, hex.EncodeToString(root[:]), nil
+}
+
+// CalcMerkleRoot inserts commitments, in order, into a new in-memory RLN
+// instance and returns the resulting Merkle root, letting callers check a
+// known group's root without keeping an RLN instance of their own around.
+func CalcMerkleRoot(commitments []IDCommitment, params []byte) (MerkleNode, error) {
+	r, err := NewRLN(params)
+	if err != nil {
+		return MerkleNode{}, fmt.Errorf("creating RLN instance: %w", err)
+	}
+
+	for i, commitment := range commitments {
+		if !r.InsertMember(commitment) {
+			return MerkleNode{}, fmt.Errorf("inserting commitment %d", i)
+		}
+	}
+
+	return r.GetMerkleRoot()
+}
+
+// toMembershipKeyPairs parses a "<secret hex>:<commitment hex>" fixture
+// list (see STATIC_GROUP_KEYS) into MembershipKeyPairs, without needing a
+// live RLN instance: the secret and commitment were derived once off to the
+// side and are taken as given here.
+func toMembershipKeyPairs(groupKeys []string) ([]MembershipKeyPair, error) {
+	pairs := make([]MembershipKeyPair, 0, len(groupKeys))
+	for i, entry := range groupKeys {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("group key %d: expected \"secret:commitment\", got %q", i, entry)
+		}
+
+		secretBytes, err := hex.DecodeString(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("group key %d: decoding secret: %w", i, err)
+		}
+		commitmentBytes, err := hex.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("group key %d: decoding commitment: %w", i, err)
+		}
+
+		var secret IDKey
+		var commitment IDCommitment
+		copy(secret[:], secretBytes)
+		copy(commitment[:], commitmentBytes)
+
+		pairs = append(pairs, newMembershipKeyPairFromSecret(secret, commitment))
+	}
+
+	return pairs, nil
+}
+
+// STATIC_GROUP_KEYS is a fixed set of "<secret hex>:<commitment hex>"
+// membership keys used by TestCheckCorrectness as a stable group to check
+// CalcMerkleRoot against, instead of freshly-generated, non-reproducible
+// keys.
+var STATIC_GROUP_KEYS = []string{
+	"7d1cbb79272212dee7f6d6970cd0f023d9e80eecba53d5141f308ba0dddc42d4:c8996995f97d977cddadb6b083c8702f579df1ae988087a2a79444edbfb63c78",
+	"aaed18d5c81ba9fe3ceb8ea7402db3e7d4c071758060cf987fe7712c8033ae88:c895fc0886e1251389ff14263895b9fb14724a13493e0527d741c4cc5d88be22",
+	"6a29bddc0b715e26bf8e9b31af4a2cbf7bbbd67530a1a45bc09d9dbbbe048403:f48d16adedc39b6fa97d0d9f5563430217d0ed1ca4799b741fa23986882d42dc",
+	"a867c2e04a432eabc50f0f4a4689a8ea619f9c02e642bc3d70b7f3077b3b4c93:45d8103c4c66357e5938ea6c928e2f5e428f54d8973384e3442330a29a5279a0",
+	"a8024bbe507e1d9bf1afa001b9fd63b73f0f1b3e3cd8232c43f387b33968cbe4:57701fe31f59806944bd271d3d3424dab31de436d210ed8fbb826f7755dadc5c",
+}
+
+// STATIC_GROUP_SIZE is the number of members in STATIC_GROUP_KEYS.
+var STATIC_GROUP_SIZE = len(STATIC_GROUP_KEYS)
+
+// STATIC_GROUP_MERKLE_ROOT is CalcMerkleRoot of STATIC_GROUP_KEYS's
+// commitments, inserted in order, against the default (!zerokit) backend.
+// Recompute it (see rln_test.go's TestCheckCorrectness) if STATIC_GROUP_KEYS
+// changes.
+var STATIC_GROUP_MERKLE_ROOT = "6425bcf7b4ee250d8333e643c1440084ac34cd5eb5944acb9aea3d0008a2a5b5"
@@ -0,0 +1,246 @@
+package rln
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/waku-org/go-rln/rln/contract"
+)
+
+// RegistrationHandler is invoked once a Register call's transaction has been
+// mined, so that callers can persist the tx hash and assigned membership
+// index (e.g. into a keystore) before the node starts relaying.
+type RegistrationHandler func(tx *types.Transaction, index MembershipIndex)
+
+// OnchainGroupManager keeps a local RLN instance's Merkle tree synchronized
+// with the members mapping of an on-chain membership registry, mirroring
+// nwaku's on-chain rln-relay mode: it registers the local identity
+// commitment, then listens for MemberRegistered/MemberWithdrawn events and
+// replays them into InsertMember/DeleteMember.
+type OnchainGroupManager struct {
+	rln             *RLN
+	client          *ethclient.Client
+	contract        *contract.MembershipContract
+	contractAddress common.Address
+	privKey         *ecdsa.PrivateKey
+
+	// startBlock is the block from which past events are replayed on Start.
+	startBlock uint64
+
+	registrationHandler RegistrationHandler
+
+	cancel context.CancelFunc
+}
+
+// NewOnchainGroupManager dials rpcURL, binds to the membership contract at
+// contractAddress, and returns a manager able to register and synchronize
+// rln's Merkle tree with that contract.
+func NewOnchainGroupManager(rpcURL string, contractAddress common.Address, privKey *ecdsa.PrivateKey, rln *RLN) (*OnchainGroupManager, error) {
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("dialing rpc endpoint: %w", err)
+	}
+
+	membershipContract, err := contract.NewMembershipContract(contractAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("binding membership contract: %w", err)
+	}
+
+	return &OnchainGroupManager{
+		rln:             rln,
+		client:          client,
+		contract:        membershipContract,
+		contractAddress: contractAddress,
+		privKey:         privKey,
+	}, nil
+}
+
+// SetRegistrationHandler registers a callback fired after a successful
+// Register call's transaction is mined.
+func (gm *OnchainGroupManager) SetRegistrationHandler(handler RegistrationHandler) {
+	gm.registrationHandler = handler
+}
+
+// Register generates a membership keypair locally, deposits
+// MEMBERSHIP_DEPOSIT to the contract with the resulting identity commitment,
+// and waits for the transaction to be mined. It returns the membership index
+// assigned by the contract's MemberRegistered event and the registration
+// transaction hash.
+func (gm *OnchainGroupManager) Register(ctx context.Context) (MembershipIndex, common.Hash, error) {
+	keyPair, err := gm.rln.MembershipKeyGen()
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("generating membership keypair: %w", err)
+	}
+
+	deposit, err := gm.contract.MembershipDeposit(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("fetching membership deposit: %w", err)
+	}
+
+	chainID, err := gm.client.ChainID(ctx)
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("fetching chain id: %w", err)
+	}
+
+	auth, err := bind.NewKeyedTransactorWithChainID(gm.privKey, chainID)
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("creating transactor: %w", err)
+	}
+	auth.Context = ctx
+	auth.Value = deposit
+
+	pubkey := new(big.Int).SetBytes(keyPair.IDCommitment[:])
+
+	tx, err := gm.contract.Register(auth, pubkey)
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("sending register tx: %w", err)
+	}
+
+	receipt, err := bind.WaitMined(ctx, gm.client, tx)
+	if err != nil {
+		return 0, common.Hash{}, fmt.Errorf("waiting for register tx: %w", err)
+	}
+
+	var index MembershipIndex
+	for _, log := range receipt.Logs {
+		event, err := gm.parseMemberRegistered(*log)
+		if err != nil {
+			continue
+		}
+		if event.Pubkey.Cmp(pubkey) == 0 {
+			index = MembershipIndex(event.Index.Uint64())
+			break
+		}
+	}
+
+	if gm.registrationHandler != nil {
+		gm.registrationHandler(tx, index)
+	}
+
+	return index, tx.Hash(), nil
+}
+
+func (gm *OnchainGroupManager) parseMemberRegistered(log types.Log) (*contract.MemberRegistered, error) {
+	events, err := gm.contract.FilterMemberRegistered(&bind.FilterOpts{Start: log.BlockNumber, End: &log.BlockNumber})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		if e.Raw.TxHash == log.TxHash && e.Raw.Index == log.Index {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("MemberRegistered event not found in log")
+}
+
+// Start replays MemberRegistered/MemberWithdrawn events from startBlock
+// onward to reconcile the local Merkle tree with the contract's pubkeyIndex
+// mapping, then subscribes to new events for the lifetime of ctx.
+func (gm *OnchainGroupManager) Start(ctx context.Context, startBlock uint64) error {
+	gm.startBlock = startBlock
+	ctx, cancel := context.WithCancel(ctx)
+	gm.cancel = cancel
+
+	if err := gm.replay(ctx); err != nil {
+		cancel()
+		return fmt.Errorf("replaying past events: %w", err)
+	}
+
+	registered := make(chan *contract.MemberRegistered)
+	registeredSub, err := gm.contract.WatchMemberRegistered(&bind.WatchOpts{Context: ctx, Start: &startBlock}, registered)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("subscribing to MemberRegistered: %w", err)
+	}
+
+	withdrawn := make(chan *contract.MemberWithdrawn)
+	withdrawnSub, err := gm.contract.WatchMemberWithdrawn(&bind.WatchOpts{Context: ctx, Start: &startBlock}, withdrawn)
+	if err != nil {
+		registeredSub.Unsubscribe()
+		cancel()
+		return fmt.Errorf("subscribing to MemberWithdrawn: %w", err)
+	}
+
+	go func() {
+		defer registeredSub.Unsubscribe()
+		defer withdrawnSub.Unsubscribe()
+		for {
+			select {
+			case event := <-registered:
+				var commitment IDCommitment
+				event.Pubkey.FillBytes(commitment[:])
+				if !gm.rln.InsertMember(commitment) {
+					log.Printf("rln: failed to insert member at index %d from MemberRegistered event", event.Index.Uint64())
+				}
+			case event := <-withdrawn:
+				if !gm.rln.DeleteMember(MembershipIndex(event.Index.Uint64())) {
+					log.Printf("rln: failed to delete member at index %d from MemberWithdrawn event", event.Index.Uint64())
+				}
+			case err := <-registeredSub.Err():
+				log.Printf("rln: MemberRegistered subscription error: %v", err)
+				return
+			case err := <-withdrawnSub.Err():
+				log.Printf("rln: MemberWithdrawn subscription error: %v", err)
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop cancels the subscription started by Start.
+func (gm *OnchainGroupManager) Stop() {
+	if gm.cancel != nil {
+		gm.cancel()
+	}
+}
+
+func (gm *OnchainGroupManager) replay(ctx context.Context) error {
+	opts := &bind.FilterOpts{Start: gm.startBlock, Context: ctx}
+
+	registrations, err := gm.contract.FilterMemberRegistered(opts)
+	if err != nil {
+		return err
+	}
+
+	withdrawals, err := gm.contract.FilterMemberWithdrawn(opts)
+	if err != nil {
+		return err
+	}
+
+	withdrawnIndices := make(map[uint64]bool, len(withdrawals))
+	for _, w := range withdrawals {
+		withdrawnIndices[w.Index.Uint64()] = true
+	}
+
+	for _, r := range registrations {
+		index := r.Index.Uint64()
+
+		var commitment IDCommitment
+		r.Pubkey.FillBytes(commitment[:])
+		if !gm.rln.InsertMember(commitment) {
+			return fmt.Errorf("inserting registered member at index %d", index)
+		}
+
+		// A withdrawn member still occupies its leaf index: it is inserted
+		// and then cleared, so later members keep their contract indices.
+		if withdrawnIndices[index] {
+			if !gm.rln.DeleteMember(MembershipIndex(index)) {
+				return fmt.Errorf("deleting withdrawn member at index %d", index)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+// Package keystore persists RLN membership credentials to disk, encrypted
+// with a user-supplied password, so that a node does not lose its on-chain
+// membership across restarts. It is the Go counterpart of nwaku's
+// waku_keystore module and follows the same JSON schema (an
+// EIP-2335 / Web3-Secret-Storage encrypted payload per credential, keyed by
+// a hash of the membership contract address and chain id) so keystore files
+// can be shared between the Go and Nim implementations.
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/waku-org/go-rln/rln"
+)
+
+// application/appIdentifier/version identify the keystore file format; they
+// are fixed so that Go- and Nim-produced keystores parse identically.
+const (
+	application   = "waku-rln-relay"
+	appIdentifier = "membership-keystore"
+	version       = 1
+)
+
+// MembershipCredential bundles everything needed to relay on a given RLN
+// membership: the local key material, the index the contract assigned on
+// registration, and the contract it was registered against.
+type MembershipCredential struct {
+	MembershipKeyPair rln.MembershipKeyPair
+	MembershipIndex   rln.MembershipIndex
+	ContractAddress   common.Address
+	ChainID           *big.Int
+}
+
+// keystoreFile is the on-disk JSON representation, matching nwaku's
+// waku_keystore schema.
+type keystoreFile struct {
+	Application   string                         `json:"application"`
+	AppIdentifier string                         `json:"appIdentifier"`
+	Version       int                            `json:"version"`
+	Credentials   map[string]encryptedCredential `json:"credentials"`
+}
+
+// encryptedCredential wraps a Web3 Secret Storage encrypted blob around the
+// JSON-serialized MembershipCredential.
+type encryptedCredential struct {
+	Crypto keystore.CryptoJSON `json:"crypto"`
+}
+
+// credentialKey hashes the contract address and chain id into the map key
+// nwaku uses to look up a credential without knowing the password.
+func credentialKey(contractAddress common.Address, chainID *big.Int) string {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(contractAddress.Bytes())
+	hash.Write(chainID.Bytes())
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// SaveMembershipCredentials encrypts kp/membershipIndex/contractAddr with
+// password (scrypt for key derivation, AES-128-CTR for the cipher, per the
+// Web3 Secret Storage spec) and stores the result in the keystore file at
+// path, creating it if it does not exist yet. The credential is stored under
+// credentialKey(contractAddr, chainID), replacing any credential already
+// stored there for the same contract and chain.
+func SaveMembershipCredentials(path string, password string, kp rln.MembershipKeyPair, membershipIndex rln.MembershipIndex, contractAddr common.Address, chainID *big.Int) error {
+	ks, err := readOrCreate(path)
+	if err != nil {
+		return err
+	}
+
+	credential := MembershipCredential{
+		MembershipKeyPair: kp,
+		MembershipIndex:   membershipIndex,
+		ContractAddress:   contractAddr,
+		ChainID:           chainID,
+	}
+
+	plaintext, err := json.Marshal(credential)
+	if err != nil {
+		return fmt.Errorf("marshaling credential: %w", err)
+	}
+
+	cryptoJSON, err := keystore.EncryptDataV3(plaintext, []byte(password), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return fmt.Errorf("encrypting credential: %w", err)
+	}
+
+	ks.Credentials[credentialKey(contractAddr, chainID)] = encryptedCredential{Crypto: cryptoJSON}
+
+	return write(path, ks)
+}
+
+// LoadMembershipCredentials decrypts and returns every credential in the
+// keystore file at path that password successfully decrypts. Credentials
+// encrypted with a different password are silently skipped, matching
+// nwaku's behaviour of treating the keystore as a set of credentials that
+// may belong to different users.
+func LoadMembershipCredentials(path string, password string) ([]MembershipCredential, error) {
+	ks, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var credentials []MembershipCredential
+	for key, encrypted := range ks.Credentials {
+		plaintext, err := keystore.DecryptDataV3(encrypted.Crypto, password)
+		if err != nil {
+			continue
+		}
+
+		var credential MembershipCredential
+		if err := json.Unmarshal(plaintext, &credential); err != nil {
+			return nil, fmt.Errorf("unmarshaling credential %s: %w", key, err)
+		}
+
+		credentials = append(credentials, credential)
+	}
+
+	return credentials, nil
+}
+
+func readOrCreate(path string) (*keystoreFile, error) {
+	ks, err := read(path)
+	if os.IsNotExist(err) {
+		return &keystoreFile{
+			Application:   application,
+			AppIdentifier: appIdentifier,
+			Version:       version,
+			Credentials:   map[string]encryptedCredential{},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+func read(path string) (*keystoreFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var ks keystoreFile
+	if err := json.Unmarshal(raw, &ks); err != nil {
+		return nil, fmt.Errorf("parsing keystore %s: %w", path, err)
+	}
+
+	return &ks, nil
+}
+
+func write(path string, ks *keystoreFile) error {
+	raw, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling keystore: %w", err)
+	}
+
+	return os.WriteFile(path, raw, 0600)
+}
@@ -0,0 +1,52 @@
+package keystore
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/waku-org/go-rln/rln"
+)
+
+func TestKeystoreSuite(t *testing.T) {
+	suite.Run(t, new(KeystoreSuite))
+}
+
+type KeystoreSuite struct {
+	suite.Suite
+}
+
+func (s *KeystoreSuite) TestSaveAndLoadMembershipCredentials() {
+	path := filepath.Join(s.T().TempDir(), "keystore.json")
+
+	kp := rln.MembershipKeyPair{}
+	contractAddr := common.HexToAddress("0x0123456789012345678901234567890123456789")
+	chainID := big.NewInt(1)
+
+	err := SaveMembershipCredentials(path, "s3cr3t", kp, rln.MembershipIndex(7), contractAddr, chainID)
+	s.NoError(err)
+
+	credentials, err := LoadMembershipCredentials(path, "s3cr3t")
+	s.NoError(err)
+	s.Len(credentials, 1)
+	s.Equal(rln.MembershipIndex(7), credentials[0].MembershipIndex)
+	s.Equal(contractAddr, credentials[0].ContractAddress)
+}
+
+func (s *KeystoreSuite) TestLoadWithWrongPasswordSkipsCredential() {
+	path := filepath.Join(s.T().TempDir(), "keystore.json")
+
+	kp := rln.MembershipKeyPair{}
+	contractAddr := common.HexToAddress("0x0123456789012345678901234567890123456789")
+	chainID := big.NewInt(1)
+
+	err := SaveMembershipCredentials(path, "s3cr3t", kp, rln.MembershipIndex(7), contractAddr, chainID)
+	s.NoError(err)
+
+	credentials, err := LoadMembershipCredentials(path, "wrong-password")
+	s.NoError(err)
+	s.Len(credentials, 0)
+}
@@ -0,0 +1,91 @@
+package rln
+
+import "unsafe"
+
+// RLN wraps a single native backend instance (kilic/rln by default, or
+// zerokit under the `zerokit` build tag) and, optionally, the on-disk
+// storage its Merkle tree is persisted to.
+type RLN struct {
+	instance    unsafe.Pointer
+	backend     Backend
+	treeStorage TreeStorage
+}
+
+// NewRLN creates a new in-memory RLN instance at the default tree depth
+// (32), the depth the original kilic/rln-only circuit this package targets.
+func NewRLN(params []byte) (*RLN, error) {
+	return NewRLNWithDepth(32, params)
+}
+
+// NewRLNWithDepth creates a new in-memory RLN instance of the given tree
+// depth, backed by whichever native library was selected at build time via
+// the `zerokit` build tag.
+func NewRLNWithDepth(depth uint, params []byte) (*RLN, error) {
+	backend := newBackend()
+
+	instance, err := backend.NewRLN(depth, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RLN{instance: instance, backend: backend}, nil
+}
+
+// MembershipKeyGen generates a new membership keypair.
+func (r *RLN) MembershipKeyGen() (*MembershipKeyPair, error) {
+	kp, err := r.backend.MembershipKeyGen(r.instance)
+	if err != nil {
+		return nil, err
+	}
+	return &kp, nil
+}
+
+// Hash returns the hash of input used internally to derive IDCommitments and
+// Merkle tree nodes: the real native backends (kilic/rln, zerokit) use
+// Poseidon; the pure-Go placeholder implementations this package currently
+// ships (see ffi_kilic.go, ffi_zerokit.go) use a domain-tagged SHA-256
+// instead, so callers should not rely on this matching Poseidon output
+// until the real cgo bindings are wired in.
+func (r *RLN) Hash(input []byte) (MerkleNode, error) {
+	return r.backend.Hash(r.instance, input)
+}
+
+// GenerateProof produces a RateLimitProof that msg was sent by the member
+// at index, for the given epoch.
+func (r *RLN) GenerateProof(msg []byte, key MembershipKeyPair, index MembershipIndex, epoch Epoch) (*RateLimitProof, error) {
+	return r.backend.GenerateProof(r.instance, msg, key, index, epoch)
+}
+
+// Verify checks that proof is a valid rate-limit proof for msg.
+func (r *RLN) Verify(msg []byte, proof RateLimitProof) bool {
+	ok, err := r.backend.Verify(r.instance, msg, proof)
+	if err != nil {
+		return false
+	}
+	return ok
+}
+
+// InsertMember adds commitment as the next leaf of the Merkle tree.
+func (r *RLN) InsertMember(commitment IDCommitment) bool {
+	ok, err := r.backend.InsertMember(r.instance, commitment)
+	if err != nil {
+		return false
+	}
+	r.syncRootMarker()
+	return ok
+}
+
+// DeleteMember clears the leaf at index, resetting it to the zero value.
+func (r *RLN) DeleteMember(index MembershipIndex) bool {
+	ok, err := r.backend.DeleteMember(r.instance, index)
+	if err != nil {
+		return false
+	}
+	r.syncRootMarker()
+	return ok
+}
+
+// GetMerkleRoot returns the current Merkle tree root.
+func (r *RLN) GetMerkleRoot() (MerkleNode, error) {
+	return r.backend.GetMerkleRoot(r.instance)
+}
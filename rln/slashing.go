@@ -0,0 +1,164 @@
+package rln
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+)
+
+// bn254FrModulus is the order of the BN254 scalar field Fr, over which the
+// RLN secret-sharing (and therefore the Lagrange interpolation used to
+// recover a slashed member's IDKey) is computed.
+var bn254FrModulus, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// MessageValidationResult is the outcome of checking a message's RateLimitProof
+// against the NullifierLog of proofs already seen for its epoch.
+type MessageValidationResult int
+
+const (
+	// MessageValidationResultValid indicates the proof verified and no
+	// prior proof was seen for this nullifier/epoch.
+	MessageValidationResultValid MessageValidationResult = iota
+	// MessageValidationResultInvalid indicates the proof itself failed to
+	// verify.
+	MessageValidationResultInvalid
+	// MessageValidationResultSpam indicates a second, distinct proof was
+	// seen for the same nullifier/epoch, i.e. the sender rate-limited
+	// themselves and the recovered IDKey can be used to slash them.
+	MessageValidationResultSpam
+)
+
+// ProofMetadata is the slice of a RateLimitProof that CheckSpam needs to
+// keep around per epoch in order to detect a double-signal and recover the
+// offending member's secret.
+type ProofMetadata struct {
+	Nullifier Nullifier
+	ShareX    MerkleNode
+	ShareY    MerkleNode
+}
+
+// NullifierLog records, per epoch, the metadata of every proof CheckSpam has
+// seen so far, so a second proof reusing a nullifier can be detected and
+// used to recover the sender's IDKey.
+type NullifierLog struct {
+	entries map[Epoch][]ProofMetadata
+}
+
+// NewNullifierLog returns an empty NullifierLog.
+func NewNullifierLog() *NullifierLog {
+	return &NullifierLog{entries: make(map[Epoch][]ProofMetadata)}
+}
+
+// insert records proof's metadata under its epoch and returns any prior
+// entry sharing the same nullifier, which is the other half of a
+// double-signal.
+func (l *NullifierLog) insert(epoch Epoch, metadata ProofMetadata) *ProofMetadata {
+	for _, existing := range l.entries[epoch] {
+		if bytes.Equal(existing.Nullifier[:], metadata.Nullifier[:]) {
+			found := existing
+			l.entries[epoch] = append(l.entries[epoch], metadata)
+			return &found
+		}
+	}
+
+	l.entries[epoch] = append(l.entries[epoch], metadata)
+	return nil
+}
+
+// CheckSpam verifies proof against msg and records it in log. If a second
+// proof reusing the same nullifier for the same epoch is found, it returns
+// MessageValidationResultSpam along with the IDKey recovered from the two
+// shares; otherwise it returns Valid/Invalid depending on proof
+// verification.
+func (r *RLN) CheckSpam(msg []byte, proof RateLimitProof, log *NullifierLog) (MessageValidationResult, IDKey, error) {
+	if !r.Verify(msg, proof) {
+		return MessageValidationResultInvalid, IDKey{}, nil
+	}
+
+	metadata := ProofMetadata{
+		Nullifier: proof.Nullifier,
+		ShareX:    proof.ShareX,
+		ShareY:    proof.ShareY,
+	}
+
+	prior := log.insert(proof.Epoch, metadata)
+	if prior == nil {
+		return MessageValidationResultValid, IDKey{}, nil
+	}
+
+	secret, err := recoverIDSecret(proof.Epoch, proof.Epoch, *prior, metadata)
+	if err != nil {
+		return MessageValidationResultSpam, IDKey{}, fmt.Errorf("recovering id secret: %w", err)
+	}
+
+	return MessageValidationResultSpam, secret, nil
+}
+
+// RecoverIDSecret recovers the IDKey shared by two RateLimitProofs generated
+// by the same member for the same epoch, via Lagrange interpolation of the
+// two (shareX, shareY) points over the BN254 scalar field. It returns an
+// error if the proofs are not a valid double-signal (different epochs,
+// identical shareX) or if the recovered key does not hash to
+// expectedCommitment. Deriving that hash needs a live native instance (the
+// same Poseidon hash IDCommitments are derived from in MembershipKeyGen), so
+// this is a method on *RLN rather than a free function.
+func (r *RLN) RecoverIDSecret(proof1, proof2 RateLimitProof, expectedCommitment IDCommitment) (IDKey, error) {
+	if !bytes.Equal(proof1.Epoch[:], proof2.Epoch[:]) {
+		return IDKey{}, fmt.Errorf("proofs are from different epochs")
+	}
+
+	if bytes.Equal(proof1.ShareX[:], proof2.ShareX[:]) {
+		return IDKey{}, fmt.Errorf("proofs share the same shareX, cannot interpolate")
+	}
+
+	secret, err := recoverIDSecret(proof1.Epoch, proof2.Epoch,
+		ProofMetadata{Nullifier: proof1.Nullifier, ShareX: proof1.ShareX, ShareY: proof1.ShareY},
+		ProofMetadata{Nullifier: proof2.Nullifier, ShareX: proof2.ShareX, ShareY: proof2.ShareY},
+	)
+	if err != nil {
+		return IDKey{}, err
+	}
+
+	commitment, err := r.Hash(secret[:])
+	if err != nil {
+		return IDKey{}, fmt.Errorf("deriving commitment from recovered key: %w", err)
+	}
+
+	if !bytes.Equal(commitment[:], expectedCommitment[:]) {
+		return IDKey{}, fmt.Errorf("recovered key does not match expected commitment")
+	}
+
+	return secret, nil
+}
+
+// recoverIDSecret runs the two-point Lagrange interpolation at x=0 over the
+// BN254 scalar field: given (x1, y1) and (x2, y2) on the line y = secret +
+// a*x, secret = y1 - x1*(y2-y1)/(x2-x1).
+func recoverIDSecret(epoch1, epoch2 Epoch, a, b ProofMetadata) (IDKey, error) {
+	if !bytes.Equal(epoch1[:], epoch2[:]) {
+		return IDKey{}, fmt.Errorf("epochs do not match")
+	}
+
+	x1 := new(big.Int).SetBytes(a.ShareX[:])
+	y1 := new(big.Int).SetBytes(a.ShareY[:])
+	x2 := new(big.Int).SetBytes(b.ShareX[:])
+	y2 := new(big.Int).SetBytes(b.ShareY[:])
+
+	if x1.Cmp(x2) == 0 {
+		return IDKey{}, fmt.Errorf("shareX values are identical, cannot interpolate")
+	}
+
+	slopeNum := new(big.Int).Mod(new(big.Int).Sub(y2, y1), bn254FrModulus)
+	slopeDen := new(big.Int).Mod(new(big.Int).Sub(x2, x1), bn254FrModulus)
+	slopeDenInv := new(big.Int).ModInverse(slopeDen, bn254FrModulus)
+	if slopeDenInv == nil {
+		return IDKey{}, fmt.Errorf("shareX difference has no modular inverse")
+	}
+	slope := new(big.Int).Mod(new(big.Int).Mul(slopeNum, slopeDenInv), bn254FrModulus)
+
+	secret := new(big.Int).Mod(new(big.Int).Sub(y1, new(big.Int).Mul(x1, slope)), bn254FrModulus)
+
+	var key IDKey
+	secret.FillBytes(key[:])
+	return key, nil
+}
@@ -0,0 +1,209 @@
+package rln
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TreeStorage persists the Merkle tree backing an RLN instance so that it
+// can be reopened across restarts without replaying the entire membership
+// set. Implementations are expected to be safe for the lifetime of a single
+// RLN instance; they are not required to be safe for concurrent use by
+// multiple instances pointed at the same path.
+type TreeStorage interface {
+	// Path returns the on-disk location backing this storage.
+	Path() string
+
+	// Flush persists any buffered tree state to disk.
+	Flush() error
+
+	// Close releases resources held by the storage (e.g. an open LevelDB
+	// handle).
+	Close() error
+}
+
+// fileTreeStorage is the default TreeStorage, backed by a directory managed
+// by the underlying FFI RLN instance (the pure-Go placeholder backends in
+// ffi_kilic.go and ffi_zerokit.go persist the sparse Merkle tree there as a
+// single JSON file).
+type fileTreeStorage struct {
+	path string
+}
+
+// newFileTreeStorage opens (creating if necessary) the tree storage
+// directory at path. The directory's contents are owned by the FFI RLN
+// instance; this type only tracks the path and exposes Flush/Close.
+func newFileTreeStorage(path string) (*fileTreeStorage, error) {
+	if path == "" {
+		return nil, fmt.Errorf("tree path must not be empty")
+	}
+	return &fileTreeStorage{path: path}, nil
+}
+
+func (s *fileTreeStorage) Path() string {
+	return s.path
+}
+
+func (s *fileTreeStorage) Flush() error {
+	// Flushing is handled by the FFI instance itself; kept as a no-op hook
+	// so callers have a stable place to persist state explicitly.
+	return nil
+}
+
+func (s *fileTreeStorage) Close() error {
+	return nil
+}
+
+// NewRLNWithTreePath creates a new RLN instance whose Merkle tree is backed
+// by persistent storage rooted at treePath, analogous to nwaku's
+// createRlnInstance(tree_path = ...). If treePath already contains a tree
+// from a previous run, it is reopened and its root is verified against
+// GetMerkleRoot before the instance is returned, rather than starting from
+// an empty tree.
+func NewRLNWithTreePath(depth uint, params []byte, treePath string) (*RLN, error) {
+	storage, err := newFileTreeStorage(treePath)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := newBackend()
+
+	instance, err := backend.NewRLNWithTreePath(depth, params, treePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening persistent tree at %s: %w", treePath, err)
+	}
+
+	rln := &RLN{instance: instance, backend: backend, treeStorage: storage}
+
+	if err := rln.verifyPersistedRoot(); err != nil {
+		return nil, fmt.Errorf("verifying persisted tree at %s: %w", treePath, err)
+	}
+
+	return rln, nil
+}
+
+// rootMarkerFile is where fileTreeStorage records the Merkle root it last
+// saw, inside the tree directory next to the tree file the FFI instance
+// itself writes, so a later reopen has something to verify the reloaded
+// tree against.
+func (s *fileTreeStorage) rootMarkerFile() string {
+	return filepath.Join(s.path, ".merkle-root")
+}
+
+// writeRoot persists root as the last-known-good root for this storage's
+// path, overwriting whatever was recorded before.
+func (s *fileTreeStorage) writeRoot(root MerkleNode) error {
+	if err := os.MkdirAll(s.path, 0o755); err != nil {
+		return fmt.Errorf("creating tree path: %w", err)
+	}
+	return os.WriteFile(s.rootMarkerFile(), []byte(hex.EncodeToString(root[:])), 0o644)
+}
+
+// readRoot returns the root persisted by the most recent writeRoot, or
+// found=false if this path has never had one written (e.g. a brand new
+// tree directory).
+func (s *fileTreeStorage) readRoot() (root MerkleNode, found bool, err error) {
+	raw, err := os.ReadFile(s.rootMarkerFile())
+	if os.IsNotExist(err) {
+		return MerkleNode{}, false, nil
+	}
+	if err != nil {
+		return MerkleNode{}, false, err
+	}
+
+	decoded, err := hex.DecodeString(string(raw))
+	if err != nil || len(decoded) != len(root) {
+		return MerkleNode{}, false, fmt.Errorf("corrupt root marker at %s", s.rootMarkerFile())
+	}
+	copy(root[:], decoded)
+	return root, true, nil
+}
+
+// syncRootMarker updates the root marker verifyPersistedRoot checks on the
+// next reopen, if this instance is backed by a fileTreeStorage. It is called
+// after every tree mutation (InsertMember, DeleteMember, InsertMembers,
+// DeleteMembers) rather than only from Flush, since kilicInsertMember and
+// friends already write the tree itself to disk on every mutation
+// regardless of whether the caller ever calls Flush: leaving the marker
+// stale until Flush would make a crash-before-Flush reopen - the exact case
+// persistent storage exists for - fail verifyPersistedRoot spuriously.
+// Errors are swallowed here, same as the rest of the mutation methods on
+// RLN; Flush surfaces write failures explicitly.
+func (r *RLN) syncRootMarker() {
+	if r.treeStorage == nil {
+		return
+	}
+	storage, ok := r.treeStorage.(*fileTreeStorage)
+	if !ok {
+		return
+	}
+	root, err := r.GetMerkleRoot()
+	if err != nil {
+		return
+	}
+	_ = storage.writeRoot(root)
+}
+
+// verifyPersistedRoot checks that the reopened FFI instance's Merkle root
+// matches the root this storage last recorded, catching a tree directory
+// that was corrupted or partially written since. On a path used for the
+// first time, there is nothing yet to compare against, so the current root
+// is simply recorded as the new baseline.
+func (r *RLN) verifyPersistedRoot() error {
+	if r.treeStorage == nil {
+		return nil
+	}
+
+	storage, ok := r.treeStorage.(*fileTreeStorage)
+	if !ok {
+		return nil
+	}
+
+	liveRoot, err := r.GetMerkleRoot()
+	if err != nil {
+		return fmt.Errorf("reading merkle root: %w", err)
+	}
+
+	persistedRoot, found, err := storage.readRoot()
+	if err != nil {
+		return fmt.Errorf("reading persisted root: %w", err)
+	}
+	if !found {
+		return storage.writeRoot(liveRoot)
+	}
+
+	if persistedRoot != liveRoot {
+		return fmt.Errorf("persisted root %x does not match reopened tree root %x", persistedRoot, liveRoot)
+	}
+
+	return nil
+}
+
+// Flush persists any pending Merkle tree changes to the on-disk
+// TreeStorage, including the root marker verifyPersistedRoot checks on the
+// next reopen. It is a no-op for in-memory instances created with
+// NewRLN/NewRLNWithDepth.
+func (r *RLN) Flush() error {
+	if r.treeStorage == nil {
+		return nil
+	}
+
+	if storage, ok := r.treeStorage.(*fileTreeStorage); ok {
+		root, err := r.GetMerkleRoot()
+		if err != nil {
+			return fmt.Errorf("reading merkle root: %w", err)
+		}
+		if err := storage.writeRoot(root); err != nil {
+			return fmt.Errorf("persisting merkle root: %w", err)
+		}
+	}
+
+	return r.treeStorage.Flush()
+}
+
+// Sync is an alias for Flush.
+func (r *RLN) Sync() error {
+	return r.Flush()
+}
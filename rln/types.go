@@ -0,0 +1,34 @@
+package rln
+
+// IDKey is a member's identity secret.
+type IDKey [32]byte
+
+// IDCommitment is the Poseidon-hash commitment to an IDKey, the value
+// actually inserted into the Merkle tree.
+type IDCommitment [32]byte
+
+// MerkleNode is a node (including the root) of the membership Merkle tree.
+type MerkleNode [32]byte
+
+// MembershipIndex is a member's position (leaf index) in the Merkle tree.
+type MembershipIndex uint
+
+// Epoch is the RLN epoch a proof was generated for; two proofs sharing an
+// epoch and nullifier are a double-signal.
+type Epoch [32]byte
+
+// Nullifier is the per-epoch, per-member value that lets a verifier detect
+// that two proofs were generated by the same member for the same epoch
+// without revealing the member's identity.
+type Nullifier [32]byte
+
+// RateLimitProof is the zero-knowledge proof returned by GenerateProof and
+// checked by Verify/CheckSpam. ShareX/ShareY are the member's Shamir secret
+// share for this epoch; two proofs with the same Nullifier let
+// RecoverIDSecret reconstruct the member's IDKey from the two shares.
+type RateLimitProof struct {
+	Nullifier Nullifier
+	ShareX    MerkleNode
+	ShareY    MerkleNode
+	Epoch     Epoch
+}